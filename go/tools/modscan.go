@@ -0,0 +1,264 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"golang.org/x/mod/modfile"
+)
+
+// DefaultCachePath is the conventional cache location relative to a repo
+// root, for callers that want on-disk caching and don't need a custom path.
+// It is not used unless a caller sets ScanOptions.CachePath to it explicitly.
+const DefaultCachePath = ".cache/build-tools/modpaths.json"
+
+// ScanOptions configures the go.mod scan performed by BuildModulePathMapContext.
+type ScanOptions struct {
+	// Concurrency is the number of goroutines used to parse go.mod files.
+	// Defaults to runtime.NumCPU() when zero.
+	Concurrency int
+	// CachePath is the file used to cache parsed go.mod results, keyed by
+	// path, mtime, and size, so unchanged trees skip re-parsing. Caching is
+	// disabled entirely when left empty, so a plain scan stays read-only;
+	// set it to DefaultCachePath (joined with root) to opt in.
+	CachePath string
+	// IgnoreGlobs are directory name globs to skip while walking, such as
+	// "vendor" or "testdata".
+	IgnoreGlobs []string
+}
+
+// modCacheEntry records enough information about a go.mod file to detect
+// whether it has changed since it was last parsed.
+type modCacheEntry struct {
+	ModTime int64      `json:"modTime"`
+	Size    int64      `json:"size"`
+	ModPath ModulePath `json:"modPath"`
+}
+
+// modCache is the on-disk cache format, keyed by absolute go.mod file path.
+type modCache map[string]modCacheEntry
+
+// BuildModulePathMap creates a map with module paths as keys and go.mod file
+// paths as values. It is a thin wrapper around BuildModulePathMapContext using
+// default ScanOptions.
+func (versionCfg versionConfig) BuildModulePathMap(root string) (ModulePathMap, error) {
+	return versionCfg.BuildModulePathMapContext(context.Background(), root, ScanOptions{})
+}
+
+// BuildModulePathMapContext creates a map with module paths as keys and go.mod
+// file paths as values by walking root with filepath.WalkDir, fanning out
+// go.mod parses across a worker pool, and reusing an on-disk cache keyed by
+// (path, mtime, size) so unchanged trees skip re-parsing.
+func (versionCfg versionConfig) BuildModulePathMapContext(ctx context.Context, root string, opts ScanOptions) (ModulePathMap, error) {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = runtime.NumCPU()
+	}
+
+	cacheEnabled := opts.CachePath != ""
+	var cache modCache
+	if cacheEnabled {
+		cache = loadModCache(opts.CachePath)
+	}
+	newCache := make(modCache)
+	var cacheMu sync.Mutex
+
+	goModPaths, err := findGoModPaths(root, opts.IgnoreGlobs)
+	if err != nil {
+		return nil, fmt.Errorf("could not walk %v: %v", root, err)
+	}
+
+	excludedModules := versionCfg.getExcludedModules()
+	modPathMap := make(ModulePathMap)
+	var mapMu sync.Mutex
+
+	// scanCtx is canceled the moment any worker fails, so the feeder
+	// goroutine below stops blocking on a send that no worker will ever
+	// read again and closes pathCh, letting the remaining workers drain out.
+	scanCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pathCh := make(chan string)
+	errCh := make(chan error, concurrency)
+	var wg sync.WaitGroup
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for filePath := range pathCh {
+				select {
+				case <-scanCtx.Done():
+					if ctx.Err() != nil {
+						errCh <- ctx.Err()
+					}
+					return
+				default:
+				}
+
+				modPath, info, err := parseGoModCached(filePath, cache)
+				if err != nil {
+					errCh <- fmt.Errorf("could not parse %v: %v", filePath, err)
+					cancel()
+					return
+				}
+
+				if cacheEnabled {
+					cacheMu.Lock()
+					newCache[filePath] = modCacheEntry{ModTime: info.ModTime, Size: info.Size, ModPath: modPath}
+					cacheMu.Unlock()
+				}
+
+				if _, shouldExclude := excludedModules[modPath]; shouldExclude {
+					continue
+				}
+
+				mapMu.Lock()
+				modPathMap[modPath] = ModuleFilePath(filePath)
+				mapMu.Unlock()
+			}
+		}()
+	}
+
+	go func() {
+		defer close(pathCh)
+		for _, p := range goModPaths {
+			select {
+			case pathCh <- p:
+			case <-scanCtx.Done():
+				return
+			}
+		}
+	}()
+
+	wg.Wait()
+	close(errCh)
+	for err := range errCh {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cacheEnabled {
+		if err := writeModCache(opts.CachePath, newCache); err != nil {
+			fmt.Printf("Warning: could not write go.mod cache to %v: %v\n", opts.CachePath, err)
+		}
+	}
+
+	return modPathMap, nil
+}
+
+// findGoModPaths walks root with filepath.WalkDir, collecting the paths of
+// every go.mod file found, skipping directories whose base name matches an
+// ignore glob.
+func findGoModPaths(root string, ignoreGlobs []string) ([]string, error) {
+	var goModPaths []string
+
+	walkFn := func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			fmt.Printf("Warning: file could not be read during filepath.WalkDir(): %v\n", err)
+			return nil
+		}
+
+		if d.IsDir() {
+			base := filepath.Base(filePath)
+			for _, glob := range ignoreGlobs {
+				if matched, _ := filepath.Match(glob, base); matched {
+					return filepath.SkipDir
+				}
+			}
+			return nil
+		}
+
+		if filepath.Base(filePath) == "go.mod" {
+			goModPaths = append(goModPaths, filePath)
+		}
+		return nil
+	}
+
+	if err := filepath.WalkDir(root, walkFn); err != nil {
+		return nil, err
+	}
+
+	return goModPaths, nil
+}
+
+// fileStat is the subset of os.FileInfo needed to detect a changed go.mod.
+type fileStat struct {
+	ModTime int64
+	Size    int64
+}
+
+// parseGoModCached returns the module path declared in the go.mod file at
+// filePath, reusing cache when the file's mtime and size are unchanged, and
+// falling back to reading and parsing the file otherwise.
+func parseGoModCached(filePath string, cache modCache) (ModulePath, fileStat, error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return "", fileStat{}, err
+	}
+	stat := fileStat{ModTime: info.ModTime().UnixNano(), Size: info.Size()}
+
+	if entry, ok := cache[filePath]; ok && entry.ModTime == stat.ModTime && entry.Size == stat.Size {
+		return entry.ModPath, stat, nil
+	}
+
+	mod, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fileStat{}, err
+	}
+
+	return ModulePath(modfile.ModulePath(mod)), stat, nil
+}
+
+// loadModCache reads the on-disk go.mod cache, returning an empty cache if it
+// does not exist or cannot be parsed.
+func loadModCache(cachePath string) modCache {
+	cache := make(modCache)
+
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		return cache
+	}
+
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return make(modCache)
+	}
+
+	return cache
+}
+
+// writeModCache persists cache to cachePath, creating its parent directory if
+// needed.
+func writeModCache(cachePath string, cache modCache) error {
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0o755); err != nil {
+		return fmt.Errorf("could not create cache directory: %v", err)
+	}
+
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not marshal cache: %v", err)
+	}
+
+	return os.WriteFile(cachePath, data, 0o644)
+}
@@ -0,0 +1,181 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// commitSeparator separates the commit timestamp and hash in the `git log`
+// format string used by latestCommit. It must not appear inside an ISO-8601
+// timestamp (unlike ":"), so it is safe to split on.
+const commitSeparator = "\x00"
+
+// pseudoVersionTimestampFormat is the timestamp layout cmd/go uses inside a
+// pseudo-version, e.g. "20210101120000".
+const pseudoVersionTimestampFormat = "20060102150405"
+
+// PseudoVersion generates a Go-toolchain-compatible pseudo-version for modSet
+// based on its declared Version in versions.yaml and the latest commit
+// touching any module in the set, so downstream repos can pin an unreleased
+// combination of modules.
+func PseudoVersion(modSet ModuleSet, modPathMap ModulePathMap, repoRoot string) (string, error) {
+	commitTime, hash, err := latestCommit(modSet, modPathMap, repoRoot)
+	if err != nil {
+		return "", fmt.Errorf("could not determine latest commit for module set: %v", err)
+	}
+
+	timestamp := commitTime.UTC().Format(pseudoVersionTimestampFormat)
+	shortHash := hash
+	if len(shortHash) > 12 {
+		shortHash = shortHash[:12]
+	}
+
+	base, err := pseudoVersionBase(modSet, modPathMap, repoRoot)
+	if err != nil {
+		return "", fmt.Errorf("could not determine base version for module set: %v", err)
+	}
+
+	// base already ends in the separator ("-" or "-0.") appropriate to its form.
+	pseudo := fmt.Sprintf("%s%s-%s", base, timestamp, shortHash)
+
+	if !module.IsPseudoVersion(pseudo) {
+		return "", fmt.Errorf("generated version %v is not a valid pseudo-version", pseudo)
+	}
+
+	return pseudo, nil
+}
+
+// latestCommit returns the commit time and full hash of the most recent
+// commit affecting any module's go.mod directory in modSet, using
+// `git log -1 --format=%cI<NUL>%H` per module and taking the newest result
+// since all modules in a set share a single version tag. The NUL separator
+// (rather than ":") is required because the ISO-8601 timestamp itself
+// contains colons.
+func latestCommit(modSet ModuleSet, modPathMap ModulePathMap, repoRoot string) (time.Time, string, error) {
+	var newestTime time.Time
+	var newestHash string
+
+	for _, modPath := range modSet.Modules {
+		modFilePath, exists := modPathMap[modPath]
+		if !exists {
+			return time.Time{}, "", fmt.Errorf("could not find module path %v in path map", modPath)
+		}
+
+		modDir := filepath.Dir(string(modFilePath))
+
+		cmd := exec.Command("git", "log", "-1", "--format=%cI"+commitSeparator+"%H")
+		cmd.Dir = repoRoot
+		// Pathspec on the module's directory, not just its go.mod file, so
+		// commits to the module's source are considered, not only to go.mod.
+		cmd.Args = append(cmd.Args, "--", modDir)
+
+		out, err := cmd.Output()
+		if err != nil {
+			return time.Time{}, "", fmt.Errorf("git log failed for %v: %v", modFilePath, err)
+		}
+
+		line := strings.TrimSpace(string(out))
+		if line == "" {
+			continue
+		}
+
+		parts := strings.SplitN(line, commitSeparator, 2)
+		if len(parts) != 2 {
+			return time.Time{}, "", fmt.Errorf("unexpected git log output %q for %v", line, modFilePath)
+		}
+
+		commitTime, err := time.Parse(time.RFC3339, parts[0])
+		if err != nil {
+			return time.Time{}, "", fmt.Errorf("could not parse commit time %q: %v", parts[0], err)
+		}
+
+		if commitTime.After(newestTime) {
+			newestTime = commitTime
+			newestHash = parts[1]
+		}
+	}
+
+	if newestHash == "" {
+		return time.Time{}, "", fmt.Errorf("no commits found for any module in set")
+	}
+
+	return newestTime, newestHash, nil
+}
+
+// pseudoVersionBase computes the "vX.Y.Z-0." prefix (without the timestamp
+// and hash suffix) to use for modSet's pseudo-version. Stable sets always
+// bump the patch of their declared version. Pre-v1 sets do the same only if
+// a prior tag for the set actually exists; otherwise there is nothing to
+// bump from and the base is v0.0.0, per cmd/go's own pseudo-version rules.
+func pseudoVersionBase(modSet ModuleSet, modPathMap ModulePathMap, repoRoot string) (string, error) {
+	if !IsStableVersion(modSet.Version) {
+		tagged, err := priorTagExists(modSet, modPathMap, repoRoot)
+		if err != nil {
+			return "", fmt.Errorf("could not check for a prior tag: %v", err)
+		}
+		if !tagged {
+			return "v0.0.0-", nil
+		}
+	}
+
+	major, minor, patch, err := parseModSetSemver(modSet.Version)
+	if err != nil {
+		return "", fmt.Errorf("could not parse module set version %v: %v", modSet.Version, err)
+	}
+	return fmt.Sprintf("v%d.%d.%d-0.", major, minor, patch+1), nil
+}
+
+// priorTagExists reports whether a git tag already exists for modSet's
+// currently declared version, by checking the tag derived from the set's
+// first module.
+func priorTagExists(modSet ModuleSet, modPathMap ModulePathMap, repoRoot string) (bool, error) {
+	if len(modSet.Modules) == 0 {
+		return false, fmt.Errorf("module set has no modules")
+	}
+
+	tagNames, err := ModulePathsToTagNames(modSet.Modules[:1], modPathMap, repoRoot)
+	if err != nil {
+		return false, fmt.Errorf("could not compute tag name: %v", err)
+	}
+
+	fullTags := CombineModuleTagNamesAndVersion(tagNames, modSet.Version)
+
+	cmd := exec.Command("git", "tag", "-l", fullTags[0])
+	cmd.Dir = repoRoot
+	out, err := cmd.Output()
+	if err != nil {
+		return false, fmt.Errorf("git tag -l failed: %v", err)
+	}
+
+	return strings.TrimSpace(string(out)) != "", nil
+}
+
+// parseModSetSemver extracts the major, minor, and patch components from a
+// module set's version string of the form "vX.Y.Z".
+func parseModSetSemver(v string) (major, minor, patch int, err error) {
+	trimmed := strings.TrimPrefix(semver.Canonical(v), "v")
+	if _, scanErr := fmt.Sscanf(trimmed, "%d.%d.%d", &major, &minor, &patch); scanErr != nil {
+		return 0, 0, 0, fmt.Errorf("could not parse semver %v: %v", v, scanErr)
+	}
+	return major, minor, patch, nil
+}
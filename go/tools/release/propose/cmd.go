@@ -0,0 +1,69 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package propose
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"go.opentelemetry.io/tools"
+)
+
+// NewProposeCmd builds the "propose" subcommand for the releasing Cobra
+// application. It prints the suggested next version and API report for a
+// module set relative to its current released tag.
+//
+//	releasing propose --module-set-name stable --base v1.4.0
+func NewProposeCmd() *cobra.Command {
+	var modSetName string
+	var base string
+
+	cmd := &cobra.Command{
+		Use:   "propose",
+		Short: "Suggests the minimum next version for a module set based on its API diff against a base version",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repoRoot, err := tools.FindRepoRoot()
+			if err != nil {
+				return fmt.Errorf("unable to find repo root: %v", err)
+			}
+
+			mv, err := tools.NewModuleVersioningInfo("versions.yaml", repoRoot)
+			if err != nil {
+				return fmt.Errorf("unable to load versioning info: %v", err)
+			}
+
+			modSet, err := mv.GetModuleSet(modSetName)
+			if err != nil {
+				return fmt.Errorf("unable to find module set %v: %v", modSetName, err)
+			}
+
+			suggested, report, err := ProposeVersion(modSet, mv.ModPathMap, repoRoot, base)
+			if err != nil {
+				return fmt.Errorf("unable to propose a version: %v", err)
+			}
+
+			fmt.Printf("Suggested version for module set %q: %v (%v)\n", modSetName, suggested, report.Verdict)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&modSetName, "module-set-name", "", "Name of module set whose next version to propose")
+	cmd.Flags().StringVar(&base, "base", "", "Currently released tag to diff against")
+	cmd.MarkFlagRequired("module-set-name")
+	cmd.MarkFlagRequired("base")
+
+	return cmd
+}
@@ -0,0 +1,351 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package propose computes API-diff-driven version suggestions for a
+// ModuleSet by comparing the exported API surface of the checked-out
+// source against a previously released base version.
+package propose // import "go.opentelemetry.io/tools/release/propose"
+
+import (
+	"fmt"
+	"go/types"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+	"golang.org/x/tools/go/packages"
+
+	"go.opentelemetry.io/tools"
+)
+
+// Verdict describes the strictest kind of API change observed across a
+// ModuleSet, in increasing order of severity.
+type Verdict string
+
+const (
+	VerdictPatch Verdict = "patch"
+	VerdictMinor Verdict = "minor"
+	VerdictMajor Verdict = "major"
+)
+
+// apiFact is a single exported identifier captured from a package's API surface.
+type apiFact struct {
+	Kind      string // "func", "type", "var", "const", or "method"
+	Signature string // canonical, package-qualified type string
+}
+
+// ModuleAPIReport summarizes the API changes detected for a single module
+// between the base version and the checked-out source.
+type ModuleAPIReport struct {
+	Added         []string
+	Removed       []string
+	Changed       []string
+	GoModBreaking bool
+	Verdict       Verdict
+}
+
+// APIReport aggregates per-module API reports for an entire ModuleSet.
+type APIReport struct {
+	ModuleReports map[tools.ModulePath]ModuleAPIReport
+	Verdict       Verdict
+}
+
+// ProposeVersion computes the minimum next version for modSet, consistent with
+// semver, by diffing the exported API of every module in the set between base
+// and the current checkout. The strictest per-module verdict determines the
+// bump applied to base, since all modules in a ModuleSet share one version.
+func ProposeVersion(modSet tools.ModuleSet, modPathMap tools.ModulePathMap, repoRoot string, base string) (string, APIReport, error) {
+	baseDir, cleanup, err := checkoutBase(repoRoot, base)
+	if err != nil {
+		return "", APIReport{}, fmt.Errorf("could not check out base version %v: %v", base, err)
+	}
+	defer cleanup()
+
+	report := APIReport{ModuleReports: make(map[tools.ModulePath]ModuleAPIReport)}
+
+	for _, modPath := range modSet.Modules {
+		modFilePath, exists := modPathMap[modPath]
+		if !exists {
+			return "", APIReport{}, fmt.Errorf("could not find module path %v in path map", modPath)
+		}
+
+		modDir := filepath.Dir(string(modFilePath))
+		relDir, err := filepath.Rel(repoRoot, modDir)
+		if err != nil {
+			return "", APIReport{}, fmt.Errorf("could not determine relative dir for module %v: %v", modPath, err)
+		}
+
+		modReport, err := diffModuleAPI(modDir, filepath.Join(baseDir, relDir))
+		if err != nil {
+			return "", APIReport{}, fmt.Errorf("could not diff API for module %v: %v", modPath, err)
+		}
+		report.ModuleReports[modPath] = modReport
+
+		if severityRank(modReport.Verdict) > severityRank(report.Verdict) {
+			report.Verdict = modReport.Verdict
+		}
+	}
+
+	suggested, err := bumpVersion(base, report.Verdict)
+	if err != nil {
+		return "", APIReport{}, fmt.Errorf("could not bump version %v: %v", base, err)
+	}
+
+	return suggested, report, nil
+}
+
+// diffModuleAPI compares the exported API and go.mod requirements of a module
+// between its current directory and the base directory checked out at the
+// prior release.
+func diffModuleAPI(curDir, baseDir string) (ModuleAPIReport, error) {
+	var modReport ModuleAPIReport
+
+	curFacts, err := loadPackageFacts(curDir)
+	if err != nil {
+		return ModuleAPIReport{}, fmt.Errorf("could not load current API facts: %v", err)
+	}
+
+	baseFacts, err := loadPackageFacts(baseDir)
+	if err != nil {
+		if _, statErr := os.Stat(filepath.Join(baseDir, "go.mod")); os.IsNotExist(statErr) {
+			// The module did not exist at the base version; every current fact is an addition.
+			baseFacts = map[string]apiFact{}
+		} else {
+			return ModuleAPIReport{}, fmt.Errorf("could not load base API facts: %v", err)
+		}
+	}
+
+	for name, fact := range curFacts {
+		baseFact, existed := baseFacts[name]
+		switch {
+		case !existed:
+			modReport.Added = append(modReport.Added, name)
+		case baseFact.Signature != fact.Signature:
+			modReport.Changed = append(modReport.Changed, name)
+		}
+	}
+	for name := range baseFacts {
+		if _, stillExists := curFacts[name]; !stillExists {
+			modReport.Removed = append(modReport.Removed, name)
+		}
+	}
+
+	breaking, err := goModBreaking(filepath.Join(baseDir, "go.mod"), filepath.Join(curDir, "go.mod"))
+	if err != nil {
+		return ModuleAPIReport{}, fmt.Errorf("could not diff go.mod requirements: %v", err)
+	}
+	modReport.GoModBreaking = breaking
+
+	switch {
+	case len(modReport.Removed) > 0 || len(modReport.Changed) > 0 || breaking:
+		modReport.Verdict = VerdictMajor
+	case len(modReport.Added) > 0:
+		modReport.Verdict = VerdictMinor
+	default:
+		modReport.Verdict = VerdictPatch
+	}
+
+	return modReport, nil
+}
+
+// loadPackageFacts loads the exported API surface of every package rooted at
+// dir into a map keyed by "<package path>.<identifier>".
+func loadPackageFacts(dir string) (map[string]apiFact, error) {
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedImports | packages.NeedTypes | packages.NeedDeps,
+		Dir:  dir,
+	}
+	pkgs, err := packages.Load(cfg, "./...")
+	if err != nil {
+		return nil, fmt.Errorf("could not load packages at %v: %v", dir, err)
+	}
+
+	if packages.PrintErrors(pkgs) > 0 {
+		return nil, fmt.Errorf("packages at %v failed to load or type-check; refusing to diff a partial API", dir)
+	}
+
+	facts := make(map[string]apiFact)
+	for _, pkg := range pkgs {
+		if pkg.Types == nil {
+			return nil, fmt.Errorf("package %v at %v has no type information", pkg.PkgPath, dir)
+		}
+		scope := pkg.Types.Scope()
+		qualifier := types.RelativeTo(pkg.Types)
+		for _, name := range scope.Names() {
+			obj := scope.Lookup(name)
+			if obj == nil || !obj.Exported() {
+				continue
+			}
+			key := pkg.PkgPath + "." + name
+			facts[key] = apiFact{
+				Kind:      kindOf(obj),
+				Signature: types.ObjectString(obj, qualifier),
+			}
+			if named, ok := obj.Type().(*types.Named); ok {
+				for i := 0; i < named.NumMethods(); i++ {
+					m := named.Method(i)
+					if !m.Exported() {
+						continue
+					}
+					mKey := key + "." + m.Name()
+					facts[mKey] = apiFact{Kind: "method", Signature: types.ObjectString(m, qualifier)}
+				}
+			}
+		}
+	}
+	return facts, nil
+}
+
+func kindOf(obj types.Object) string {
+	switch obj.(type) {
+	case *types.Func:
+		return "func"
+	case *types.TypeName:
+		return "type"
+	case *types.Const:
+		return "const"
+	case *types.Var:
+		return "var"
+	default:
+		return "other"
+	}
+}
+
+// goModBreaking reports whether curModPath introduces a new required module
+// or bumps an existing requirement's major version relative to baseModPath.
+func goModBreaking(baseModPath, curModPath string) (bool, error) {
+	baseBytes, err := os.ReadFile(baseModPath)
+	if err != nil {
+		// No go.mod at the base version means nothing to compare against.
+		return false, nil
+	}
+	curBytes, err := os.ReadFile(curModPath)
+	if err != nil {
+		return false, fmt.Errorf("could not read go.mod at %v: %v", curModPath, err)
+	}
+
+	baseFile, err := modfile.Parse(baseModPath, baseBytes, nil)
+	if err != nil {
+		return false, fmt.Errorf("could not parse base go.mod: %v", err)
+	}
+	curFile, err := modfile.Parse(curModPath, curBytes, nil)
+	if err != nil {
+		return false, fmt.Errorf("could not parse current go.mod: %v", err)
+	}
+
+	baseReq := make(map[string]string)
+	for _, r := range baseFile.Require {
+		baseReq[r.Mod.Path] = r.Mod.Version
+	}
+
+	for _, r := range curFile.Require {
+		baseVersion, existed := baseReq[r.Mod.Path]
+		if !existed {
+			return true, nil
+		}
+		if semver.Major(baseVersion) != semver.Major(r.Mod.Version) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// severityRank orders Verdict values so the strictest can be selected.
+func severityRank(v Verdict) int {
+	switch v {
+	case VerdictMajor:
+		return 2
+	case VerdictMinor:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// bumpVersion applies verdict to base, following semver and OpenTelemetry's
+// pre-v1 convention of treating breaking changes as minor bumps until a set
+// explicitly opts in to crossing v1.
+func bumpVersion(base string, verdict Verdict) (string, error) {
+	if !semver.IsValid(base) {
+		return "", fmt.Errorf("invalid base version %v", base)
+	}
+
+	major, minor, patch, err := parseSemver(base)
+	if err != nil {
+		return "", err
+	}
+
+	if major == 0 {
+		switch verdict {
+		case VerdictMajor, VerdictMinor:
+			minor++
+			patch = 0
+		case VerdictPatch:
+			patch++
+		}
+		return fmt.Sprintf("v%d.%d.%d", major, minor, patch), nil
+	}
+
+	switch verdict {
+	case VerdictMajor:
+		major++
+		minor, patch = 0, 0
+	case VerdictMinor:
+		minor++
+		patch = 0
+	case VerdictPatch:
+		patch++
+	}
+
+	return fmt.Sprintf("v%d.%d.%d", major, minor, patch), nil
+}
+
+// parseSemver extracts the major, minor, and patch components from a semver
+// string of the form "vX.Y.Z".
+func parseSemver(v string) (major, minor, patch int, err error) {
+	trimmed := strings.TrimPrefix(semver.Canonical(v), "v")
+	if _, scanErr := fmt.Sscanf(trimmed, "%d.%d.%d", &major, &minor, &patch); scanErr != nil {
+		return 0, 0, 0, fmt.Errorf("could not parse semver %v: %v", v, scanErr)
+	}
+	return major, minor, patch, nil
+}
+
+// checkoutBase checks out the given base tag into a temporary git worktree so
+// its source can be loaded for comparison without disturbing the current
+// working tree. The returned cleanup function removes the worktree.
+func checkoutBase(repoRoot, base string) (string, func(), error) {
+	worktreeDir, err := os.MkdirTemp("", "otel-propose-")
+	if err != nil {
+		return "", nil, fmt.Errorf("could not create temp dir: %v", err)
+	}
+
+	cmd := exec.Command("git", "worktree", "add", "--detach", worktreeDir, base)
+	cmd.Dir = repoRoot
+	if out, err := cmd.CombinedOutput(); err != nil {
+		os.RemoveAll(worktreeDir)
+		return "", nil, fmt.Errorf("git worktree add failed: %v: %s", err, out)
+	}
+
+	cleanup := func() {
+		removeCmd := exec.Command("git", "worktree", "remove", "--force", worktreeDir)
+		removeCmd.Dir = repoRoot
+		_ = removeCmd.Run()
+	}
+
+	return worktreeDir, cleanup, nil
+}
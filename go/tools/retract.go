@@ -0,0 +1,102 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/mod/modfile"
+)
+
+// ApplyRetractions edits every module's go.mod file so its retract block
+// exactly matches the Retracted versions configured for its ModuleSet in
+// versions.yaml. Existing retract directives that are no longer configured
+// are dropped; missing ones are added. Pass dryRun to print the per-module
+// diff without writing any files.
+func ApplyRetractions(mv ModuleVersioning, dryRun bool) error {
+	for setName, modSet := range mv.ModSetMap {
+		for _, modPath := range modSet.Modules {
+			modFilePath, exists := mv.ModPathMap[modPath]
+			if !exists {
+				return fmt.Errorf("could not find module path %v in path map", modPath)
+			}
+
+			if err := applyRetractionsToModule(string(modFilePath), modSet.Retracted, dryRun); err != nil {
+				return fmt.Errorf("could not apply retractions to module %v in set %v: %v", modPath, setName, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// applyRetractionsToModule rewrites a single go.mod file's retract block to
+// match wanted exactly.
+func applyRetractionsToModule(modFilePath string, wanted []RetractedVersion, dryRun bool) error {
+	original, err := os.ReadFile(modFilePath)
+	if err != nil {
+		return fmt.Errorf("could not read %v: %v", modFilePath, err)
+	}
+
+	modFile, err := modfile.Parse(modFilePath, original, nil)
+	if err != nil {
+		return fmt.Errorf("could not parse %v: %v", modFilePath, err)
+	}
+
+	// Drop every existing single-version retract first, then add the wanted
+	// set fresh. Rewriting from scratch (rather than adding only what's
+	// missing) keeps the block exactly in sync even if it already contains
+	// one or more of the wanted versions, and avoids AddRetract appending a
+	// duplicate line on a second run. Collect the intervals before dropping
+	// since DropRetract mutates modFile.Retract in place.
+	var toDrop []modfile.VersionInterval
+	for _, existing := range modFile.Retract {
+		if existing.VersionInterval.Low != existing.VersionInterval.High {
+			// Not a single-version retraction; leave ranges untouched.
+			continue
+		}
+		toDrop = append(toDrop, existing.VersionInterval)
+	}
+	for _, vi := range toDrop {
+		if err := modFile.DropRetract(vi); err != nil {
+			return fmt.Errorf("could not drop retract %v: %v", vi.Low, err)
+		}
+	}
+
+	for _, r := range wanted {
+		vi := modfile.VersionInterval{Low: r.Version, High: r.Version}
+		if err := modFile.AddRetract(vi, r.Reason); err != nil {
+			return fmt.Errorf("could not add retract %v: %v", r.Version, err)
+		}
+	}
+
+	modFile.Cleanup()
+	formatted, err := modFile.Format()
+	if err != nil {
+		return fmt.Errorf("could not format %v: %v", modFilePath, err)
+	}
+
+	if dryRun {
+		fmt.Printf("--- %v (dry run) ---\n%s\n", modFilePath, formatted)
+		return nil
+	}
+
+	if err := os.WriteFile(modFilePath, formatted, 0o644); err != nil {
+		return fmt.Errorf("could not write %v: %v", modFilePath, err)
+	}
+
+	return nil
+}
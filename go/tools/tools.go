@@ -18,14 +18,12 @@ package tools // import "go.opentelemetry.io/tools"
 import (
 	"errors"
 	"fmt"
-	"github.com/spf13/viper"
-	"golang.org/x/mod/modfile"
-	"golang.org/x/mod/semver"
-	"io/fs"
-	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/spf13/viper"
+	"golang.org/x/mod/semver"
 )
 
 const (
@@ -82,6 +80,16 @@ type ModuleSetMap map[string]ModuleSet
 type ModuleSet struct {
 	Version string       `mapstructure:"version"`
 	Modules []ModulePath `mapstructure:"modules"`
+	// Retracted lists prior versions of this set that have been yanked, each
+	// paired with the rationale to publish in the retract directive.
+	Retracted []RetractedVersion `mapstructure:"retracted"`
+}
+
+// RetractedVersion holds a single retracted version of a ModuleSet along with
+// the reason it was retracted, as recorded in versions.yaml.
+type RetractedVersion struct {
+	Version string `mapstructure:"version"`
+	Reason  string `mapstructure:"reason"`
 }
 
 // ModulePath holds the module import path, such as "go.opentelemetry.io/otel".
@@ -178,44 +186,6 @@ func (versionCfg versionConfig) getExcludedModules() excludedModulesSet {
 	return excludedModules
 }
 
-// BuildModulePathMap creates a map with module paths as keys and go.mod file paths as values.
-func (versionCfg versionConfig) BuildModulePathMap(root string) (ModulePathMap, error) {
-	modPathMap := make(ModulePathMap)
-
-	findGoMod := func(filePath string, info fs.FileInfo, err error) error {
-		if err != nil {
-			fmt.Printf("Warning: file could not be read during filepath.Walk(): %v", err)
-			return nil
-		}
-		if filepath.Base(filePath) == "go.mod" {
-			// read go.mod file into mod []byte
-			mod, err := ioutil.ReadFile(filePath)
-			if err != nil {
-				return err
-			}
-
-			// read path of module from go.mod file
-			modPathString := modfile.ModulePath(mod)
-
-			// convert modPath, filePath string to modulePath and moduleFilePath
-			modPath := ModulePath(modPathString)
-			modFilePath := ModuleFilePath(filePath)
-
-			excludedModules := versionCfg.getExcludedModules()
-			if _, shouldExclude := excludedModules[ModulePath(modPath)]; !shouldExclude {
-				modPathMap[modPath] = modFilePath
-			}
-		}
-		return nil
-	}
-
-	if err := filepath.Walk(string(root), findGoMod); err != nil {
-		return nil, err
-	}
-
-	return modPathMap, nil
-}
-
 // GetModuleSet fetches the ModuleSet info for a module set with input name
 func (modVersioning ModuleVersioning) GetModuleSet(modSetName string) (ModuleSet, error) {
 	modSet, exists := modVersioning.ModSetMap[modSetName]
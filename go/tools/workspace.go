@@ -0,0 +1,161 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/mod/modfile"
+)
+
+// goWorkFilename is the standard name of a Go workspace file.
+const goWorkFilename = "go.work"
+
+// defaultGoWorkVersion is the go directive written into a go.work file newly
+// created by SyncWorkFile, the minimum Go version that understands go.work.
+const defaultGoWorkVersion = "1.18"
+
+// Workspace cross-references a repo's go.work file against the module set
+// versioning configuration, so the releasing tool can tell which modules are
+// locally replaced and keep go.work in sync with versions.yaml.
+type Workspace struct {
+	repoRoot   string
+	workFile   *modfile.WorkFile
+	modPathMap ModulePathMap
+}
+
+// NewWorkspace parses the go.work file at repoRoot and cross-references its
+// use directives against mv's ModulePathMap. If repoRoot has no go.work file
+// yet, an empty one is used instead of returning an error, so a repo adopting
+// workspace mode after the fact can still construct a Workspace and call
+// SyncWorkFile to create one.
+//
+// Note this is a deliberate deviation from treating construction itself as
+// the validation step: NewWorkspace never fails on a mismatch between
+// go.work and versions.yaml. Callers that want that guarantee (e.g. the
+// releasing tool, before retagging) must call CheckConsistency explicitly.
+func NewWorkspace(repoRoot string, mv ModuleVersioning) (*Workspace, error) {
+	workFilePath := filepath.Join(repoRoot, goWorkFilename)
+
+	data, err := os.ReadFile(workFilePath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("could not read %v: %v", workFilePath, err)
+		}
+		data = nil
+	}
+
+	workFile, err := modfile.ParseWork(workFilePath, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse %v: %v", workFilePath, err)
+	}
+
+	return &Workspace{
+		repoRoot:   repoRoot,
+		workFile:   workFile,
+		modPathMap: mv.ModPathMap,
+	}, nil
+}
+
+// CheckConsistency returns an error if the set of modules declared in
+// versions.yaml and the set of modules used by go.work disagree. Unlike
+// NewWorkspace, this can be called whenever the stricter validation is
+// actually needed, without blocking construction of the Workspace itself.
+func (ws *Workspace) CheckConsistency() error {
+	useDirs := make(map[string]struct{})
+	for _, use := range ws.workFile.Use {
+		useDirs[filepath.Clean(filepath.Join(ws.repoRoot, use.Path))] = struct{}{}
+	}
+
+	versionedDirs := make(map[string]ModulePath)
+	for modPath, modFilePath := range ws.modPathMap {
+		versionedDirs[filepath.Clean(filepath.Dir(string(modFilePath)))] = modPath
+	}
+
+	for dir, modPath := range versionedDirs {
+		if _, used := useDirs[dir]; !used {
+			return fmt.Errorf("module %v (%v) is declared in versions.yaml but missing from go.work", modPath, dir)
+		}
+	}
+
+	for dir := range useDirs {
+		if _, versioned := versionedDirs[dir]; !versioned {
+			return fmt.Errorf("directory %v is used in go.work but not declared in versions.yaml", dir)
+		}
+	}
+
+	return nil
+}
+
+// ReplaceDirectives returns the local replace overrides declared in go.work,
+// keyed by the module path being replaced, so the releasing tool can skip or
+// adjust retagging for modules pointed at a local directory.
+func (ws *Workspace) ReplaceDirectives() map[ModulePath]ModulePath {
+	replacements := make(map[ModulePath]ModulePath)
+	for _, rep := range ws.workFile.Replace {
+		replacements[ModulePath(rep.Old.Path)] = ModulePath(rep.New.Path)
+	}
+	return replacements
+}
+
+// SyncWorkFile rewrites go.work so its use directives contain exactly the
+// union of non-excluded modules discovered by BuildModulePathMap, useful for
+// repos that adopt workspace mode after the fact.
+func (ws *Workspace) SyncWorkFile() error {
+	dirs := make([]string, 0, len(ws.modPathMap))
+	for _, modFilePath := range ws.modPathMap {
+		dir := filepath.Dir(string(modFilePath))
+		relDir, err := filepath.Rel(ws.repoRoot, dir)
+		if err != nil {
+			return fmt.Errorf("could not compute relative path for %v: %v", dir, err)
+		}
+		useDir := "./" + relDir
+		if relDir == "." {
+			// The repo-root module's own directory; "./." is not a valid use path.
+			useDir = "."
+		}
+		dirs = append(dirs, useDir)
+	}
+	sort.Strings(dirs)
+
+	ws.workFile.Use = nil
+	for _, dir := range dirs {
+		if err := ws.workFile.AddUse(dir, ""); err != nil {
+			return fmt.Errorf("could not add use directive for %v: %v", dir, err)
+		}
+	}
+
+	if ws.workFile.Go == nil {
+		if err := ws.workFile.AddGoStmt(defaultGoWorkVersion); err != nil {
+			return fmt.Errorf("could not add go directive: %v", err)
+		}
+	}
+
+	ws.workFile.Cleanup()
+	formatted, err := ws.workFile.Format()
+	if err != nil {
+		return fmt.Errorf("could not format go.work: %v", err)
+	}
+
+	workFilePath := filepath.Join(ws.repoRoot, goWorkFilename)
+	if err := os.WriteFile(workFilePath, formatted, 0o644); err != nil {
+		return fmt.Errorf("could not write %v: %v", workFilePath, err)
+	}
+
+	return nil
+}